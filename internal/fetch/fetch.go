@@ -0,0 +1,122 @@
+// Package fetch loads a WADL document from a URL or local file, retrying
+// HTTP sources with exponential backoff and attaching caller-supplied
+// headers.
+package fetch
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/antchfx/xmlquery"
+)
+
+// Client loads WADL documents over HTTP or from disk.
+type Client struct {
+	http    *http.Client
+	retries int
+	headers http.Header
+}
+
+// Option configures a Client at construction time.
+type Option func(*Client)
+
+// WithHeader adds a header sent with every outbound HTTP request, e.g. an
+// Authorization header for a protected WADL endpoint.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithRetries sets how many times a failed HTTP request is retried (on
+// network errors or 5xx responses), with exponential backoff between
+// attempts. The default is 0 retries.
+func WithRetries(n int) Option {
+	return func(c *Client) {
+		c.retries = n
+	}
+}
+
+// New builds a Client whose HTTP requests time out after timeout.
+func New(timeout time.Duration, opts ...Option) *Client {
+	c := &Client{
+		http:    &http.Client{Timeout: timeout},
+		headers: make(http.Header),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Load fetches source - an http(s) URL or a local file path - and parses
+// it as XML.
+func (c *Client) Load(source string) (*xmlquery.Node, error) {
+	if !strings.HasPrefix(source, "http") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("opening %q: %w", source, err)
+		}
+		defer f.Close()
+
+		return xmlquery.Parse(f)
+	}
+
+	resp, err := c.getWithRetry(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return xmlquery.Parse(resp.Body)
+}
+
+// getWithRetry issues a GET to url, retrying on network errors and 5xx
+// responses up to c.retries times with exponential backoff.
+func (c *Client) getWithRetry(url string) (*http.Response, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff(attempt))
+		}
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("building request for %q: %w", url, err)
+		}
+		req.Header = c.headers.Clone()
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("%s", resp.Status)
+			resp.Body.Close()
+			continue
+		}
+
+		if resp.StatusCode >= 400 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%s", resp.Status)
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("fetching %q: %w", url, lastErr)
+}
+
+// backoff returns the delay before the given retry attempt (1-indexed):
+// 200ms, 400ms, 800ms, and so on.
+func backoff(attempt int) time.Duration {
+	return 200 * time.Millisecond * time.Duration(uint(1)<<uint(attempt-1))
+}
@@ -0,0 +1,116 @@
+package fetch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antchfx/xmlquery"
+)
+
+const wadlBody = `<?xml version="1.0"?>
+<application xmlns="http://wadl.dev.java.net/2009/02">
+  <resources base="https://api.example.com/"/>
+</application>`
+
+func TestLoadLocalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.wadl")
+	if err := os.WriteFile(path, []byte(wadlBody), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	doc, err := New(time.Second).Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if xmlquery.FindOne(doc, "//application") == nil {
+		t.Errorf("Load(%q) didn't parse as the fixture WADL", path)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := New(time.Second).Load(filepath.Join(t.TempDir(), "missing.wadl")); err == nil {
+		t.Error("Load(missing file) = nil error, want one")
+	}
+}
+
+func TestLoadRetriesOn5xxThenSucceeds(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(wadlBody))
+	}))
+	defer srv.Close()
+
+	doc, err := New(time.Second, WithRetries(2)).Load(srv.URL)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("got %d attempts, want 2", attempts)
+	}
+
+	if xmlquery.FindOne(doc, "//application") == nil {
+		t.Error("Load didn't parse the eventual successful response")
+	}
+}
+
+func TestLoadGivesUpAfterExhaustingRetries(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	if _, err := New(time.Second, WithRetries(1)).Load(srv.URL); err == nil {
+		t.Error("Load = nil error, want one after exhausting retries")
+	}
+
+	if attempts != 2 {
+		t.Errorf("got %d attempts, want 2 (1 initial + 1 retry)", attempts)
+	}
+}
+
+func TestLoadDoesNotRetry4xx(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	if _, err := New(time.Second, WithRetries(3)).Load(srv.URL); err == nil {
+		t.Error("Load = nil error, want one for a 404")
+	}
+
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1 (no retry on 4xx)", attempts)
+	}
+}
+
+func TestLoadSendsConfiguredHeaders(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotAuth = req.Header.Get("Authorization")
+		w.Write([]byte(wadlBody))
+	}))
+	defer srv.Close()
+
+	if _, err := New(time.Second, WithHeader("Authorization", "Bearer tok")).Load(srv.URL); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if gotAuth != "Bearer tok" {
+		t.Errorf("Authorization header = %q, want %q", gotAuth, "Bearer tok")
+	}
+}
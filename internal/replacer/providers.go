@@ -0,0 +1,192 @@
+package replacer
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxFileSize bounds how much of a file. placeholder's target we'll read
+// into memory.
+const maxFileSize = 1 << 20 // 1 MiB
+
+// staticProvider serves the values collected from `-p`/`--placeholder`
+// flags, matched by the exact token.
+type staticProvider map[string]string
+
+func (s staticProvider) Lookup(token string) (string, bool, error) {
+	v, ok := s[token]
+	return v, ok, nil
+}
+
+// envProvider resolves `env.FOO` tokens via os.Getenv.
+type envProvider struct{}
+
+func (envProvider) Lookup(token string) (string, bool, error) {
+	if !strings.HasPrefix(token, "env.") {
+		return "", false, nil
+	}
+
+	return os.Getenv(strings.TrimPrefix(token, "env.")), true, nil
+}
+
+// fileProvider resolves `file./path/to/x` tokens by reading the file,
+// trimmed, guarding against oversized reads and symlinks that escape the
+// file's own directory.
+type fileProvider struct{}
+
+func (fileProvider) Lookup(token string) (string, bool, error) {
+	if !strings.HasPrefix(token, "file.") {
+		return "", false, nil
+	}
+
+	path := strings.TrimPrefix(token, "file.")
+
+	data, err := readFileSafely(path)
+	if err != nil {
+		return "", true, errUnavailable("file", token, err)
+	}
+
+	return data, true, nil
+}
+
+func readFileSafely(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+
+	resolved := path
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return "", err
+		}
+
+		dir, err := filepath.Abs(filepath.Dir(path))
+		if err != nil {
+			return "", err
+		}
+
+		absTarget, err := filepath.Abs(target)
+		if err != nil {
+			return "", err
+		}
+
+		if absTarget != dir && !strings.HasPrefix(absTarget, dir+string(os.PathSeparator)) {
+			return "", fmt.Errorf("refusing to follow symlink %q outside of %q", path, dir)
+		}
+
+		resolved = target
+
+		if info, err = os.Stat(resolved); err != nil {
+			return "", err
+		}
+	}
+
+	if info.Size() > maxFileSize {
+		return "", fmt.Errorf("file %q exceeds %d byte limit", path, maxFileSize)
+	}
+
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// httpProvider resolves `http.METHOD.url` tokens by fetching the URL and
+// caching the result for the lifetime of the Replacer.
+type httpProvider struct {
+	client *http.Client
+	mu     sync.Mutex
+	cache  map[string]string
+}
+
+func newHTTPProvider() *httpProvider {
+	return &httpProvider{
+		client: &http.Client{Timeout: 10 * time.Second},
+		cache:  make(map[string]string),
+	}
+}
+
+func (p *httpProvider) Lookup(token string) (string, bool, error) {
+	if !strings.HasPrefix(token, "http.") {
+		return "", false, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(token, "http."), ".", 2)
+	if len(parts) != 2 {
+		return "", true, errUnavailable("http", token, fmt.Errorf("expected http.METHOD.url"))
+	}
+	method, url := parts[0], parts[1]
+
+	p.mu.Lock()
+	if cached, ok := p.cache[token]; ok {
+		p.mu.Unlock()
+		return cached, true, nil
+	}
+	p.mu.Unlock()
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return "", true, errUnavailable("http", token, err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", true, errUnavailable("http", token, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", true, errUnavailable("http", token, err)
+	}
+
+	value := strings.TrimSpace(string(body))
+
+	p.mu.Lock()
+	p.cache[token] = value
+	p.mu.Unlock()
+
+	return value, true, nil
+}
+
+// generatorProvider resolves the parameter-less `uuid` and `now` tokens.
+type generatorProvider struct{}
+
+func (generatorProvider) Lookup(token string) (string, bool, error) {
+	switch token {
+	case "uuid":
+		v, err := newUUIDv4()
+		if err != nil {
+			return "", true, errUnavailable("uuid", token, err)
+		}
+		return v, true, nil
+	case "now":
+		return time.Now().UTC().Format(time.RFC3339), true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+func newUUIDv4() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
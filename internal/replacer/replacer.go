@@ -0,0 +1,144 @@
+// Package replacer resolves `{name}` placeholders in a string through an
+// ordered chain of providers: a static map, then env., file., http. and
+// generator lookups.
+package replacer
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var tokenRe = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Provider resolves a single `{name}` token. It returns ok=false when the
+// token isn't one this provider handles, so the Replacer can fall through
+// to the next provider in the chain.
+type Provider interface {
+	Lookup(token string) (value string, ok bool, err error)
+}
+
+// Replacer resolves placeholders in a string through an ordered chain of
+// Providers, falling back to a default value (and finally leaving the
+// token untouched) when none of them match.
+type Replacer struct {
+	providers    []Provider
+	defaultValue string
+}
+
+// Option configures a Replacer at construction time.
+type Option func(*Replacer)
+
+// WithDefault sets the value substituted for any token no provider
+// resolves, mirroring the existing `-r/--replace` flag.
+func WithDefault(value string) Option {
+	return func(r *Replacer) {
+		r.defaultValue = value
+	}
+}
+
+// WithUntrustedSource drops the file., env. and http. providers, so a
+// WADL pulled from an untrusted URL can't use crafted resource/param
+// names to read local files, leak environment variables, or trigger
+// SSRF requests - the tokens being resolved come straight from the
+// WADL's own content, not just the `-p` flags the user typed.
+func WithUntrustedSource() Option {
+	return func(r *Replacer) {
+		kept := r.providers[:0]
+		for _, p := range r.providers {
+			switch p.(type) {
+			case fileProvider, envProvider, *httpProvider:
+				continue
+			}
+			kept = append(kept, p)
+		}
+		r.providers = kept
+	}
+}
+
+// New builds a Replacer backed by the static `-p`/`--placeholder` values
+// plus the built-in env., file., http. and uuid/now providers, in that
+// order. Apply opts (e.g. WithUntrustedSource) for untrusted input.
+func New(static map[string]string, opts ...Option) *Replacer {
+	r := &Replacer{
+		providers: []Provider{
+			staticProvider(static),
+			envProvider{},
+			fileProvider{},
+			newHTTPProvider(),
+			generatorProvider{},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Replace substitutes every `{name}` token in s, in order: the provider
+// chain, then the default value, then the token is left as-is.
+func (r *Replacer) Replace(s string) string {
+	return tokenRe.ReplaceAllStringFunc(s, func(match string) string {
+		token := match[1 : len(match)-1]
+
+		for _, p := range r.providers {
+			value, ok, err := p.Lookup(token)
+			if !ok {
+				continue
+			}
+			if err != nil {
+				return match
+			}
+
+			// A static `-p name=file.path` value names another provider
+			// rather than being a literal value, e.g. `-p token=file./tmp/tok`
+			// or `-p tenant=env.TENANT`: run it back through the dynamic
+			// providers instead of emitting it verbatim.
+			if _, isStatic := p.(staticProvider); isStatic {
+				if resolved, handled, erred := r.resolveDynamic(value); handled {
+					if erred {
+						return match
+					}
+					return resolved
+				}
+			}
+
+			return value
+		}
+
+		if r.defaultValue != "" {
+			return r.defaultValue
+		}
+
+		return match
+	})
+}
+
+// resolveDynamic looks up value (a static provider's resolved string, not
+// the original token) against every non-static provider, so a `-p`
+// placeholder can point at an env./file./http. source instead of only
+// holding a literal. handled reports whether some provider claimed value
+// at all; erred reports whether that provider then failed.
+func (r *Replacer) resolveDynamic(value string) (resolved string, handled, erred bool) {
+	for _, p := range r.providers {
+		if _, isStatic := p.(staticProvider); isStatic {
+			continue
+		}
+
+		if v, ok, err := p.Lookup(value); ok {
+			if err != nil {
+				return "", true, true
+			}
+			return v, true, false
+		}
+	}
+
+	return "", false, false
+}
+
+// errUnavailable wraps a provider-specific failure (e.g. file too big) so
+// Replace can tell "not mine" apart from "mine, but it failed".
+func errUnavailable(provider, token string, cause error) error {
+	return fmt.Errorf("replacer: %s provider failed for %q: %w", provider, token, cause)
+}
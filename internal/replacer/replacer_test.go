@@ -0,0 +1,152 @@
+package replacer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlaceholderValueResolvesThroughFileProvider(t *testing.T) {
+	r := New(map[string]string{"token": "file.testdata/secret.txt"})
+
+	if got := r.Replace("{token}"); got != "SECRETVALUE" {
+		t.Errorf(`Replace("{token}") = %q, want "SECRETVALUE"`, got)
+	}
+}
+
+func TestPlaceholderValueResolvesThroughEnvProvider(t *testing.T) {
+	t.Setenv("REPLACER_TEST_TENANT", "acme")
+
+	r := New(map[string]string{"tenant": "env.REPLACER_TEST_TENANT"})
+
+	if got := r.Replace("{tenant}"); got != "acme" {
+		t.Errorf(`Replace("{tenant}") = %q, want "acme"`, got)
+	}
+}
+
+func TestPlaceholderLiteralValueIsUnaffected(t *testing.T) {
+	r := New(map[string]string{"slug": "myslug"})
+
+	if got := r.Replace("{slug}"); got != "myslug" {
+		t.Errorf(`Replace("{slug}") = %q, want "myslug"`, got)
+	}
+}
+
+func TestWithUntrustedSourceRemovesFileEnvAndHTTPResolution(t *testing.T) {
+	t.Setenv("REPLACER_TEST_SECRET", "leaked")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Error("http. provider must not be consulted for an untrusted source")
+	}))
+	defer srv.Close()
+
+	r := New(nil, WithUntrustedSource())
+
+	tokens := []string{
+		"{file.testdata/secret.txt}",
+		"{env.REPLACER_TEST_SECRET}",
+		"{http.GET." + srv.URL + "}",
+	}
+
+	for _, token := range tokens {
+		if got := r.Replace(token); got != token {
+			t.Errorf("Replace(%q) = %q, want it left untouched", token, got)
+		}
+	}
+}
+
+func TestWithUntrustedSourceAlsoAppliesToPlaceholderIndirection(t *testing.T) {
+	t.Setenv("REPLACER_TEST_SECRET", "leaked")
+
+	r := New(map[string]string{"token": "env.REPLACER_TEST_SECRET"}, WithUntrustedSource())
+
+	if got := r.Replace("{token}"); got != "env.REPLACER_TEST_SECRET" {
+		t.Errorf(`Replace("{token}") = %q, want the raw spec left untouched`, got)
+	}
+}
+
+func TestFileProviderRejectsOversizedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.txt")
+	if err := os.WriteFile(path, make([]byte, maxFileSize+1), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	r := New(nil)
+
+	token := "{file." + path + "}"
+	if got := r.Replace(token); got != token {
+		t.Errorf("Replace(%q) = %q, want it left untouched (size guard)", token, got)
+	}
+}
+
+func TestFileProviderRejectsSymlinkEscape(t *testing.T) {
+	outside := t.TempDir()
+	target := filepath.Join(outside, "target.txt")
+	if err := os.WriteFile(target, []byte("leaked"), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	inside := t.TempDir()
+	link := filepath.Join(inside, "link.txt")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatalf("creating symlink: %v", err)
+	}
+
+	r := New(nil)
+
+	token := "{file." + link + "}"
+	if got := r.Replace(token); got != token {
+		t.Errorf("Replace(%q) = %q, want it left untouched (symlink guard)", token, got)
+	}
+}
+
+func TestHTTPProviderFetchesAndCaches(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		hits++
+		w.Write([]byte("pong"))
+	}))
+	defer srv.Close()
+
+	r := New(nil)
+
+	token := "{http.GET." + srv.URL + "}"
+	for i := 0; i < 2; i++ {
+		if got := r.Replace(token); got != "pong" {
+			t.Errorf("Replace(%q) = %q, want %q", token, got, "pong")
+		}
+	}
+
+	if hits != 1 {
+		t.Errorf("got %d requests, want 1 (cached)", hits)
+	}
+}
+
+func TestDefaultValueUsedWhenNoProviderMatches(t *testing.T) {
+	r := New(nil, WithDefault("REDACTED"))
+
+	if got := r.Replace("{anything}"); got != "REDACTED" {
+		t.Errorf(`Replace("{anything}") = %q, want "REDACTED"`, got)
+	}
+}
+
+func TestUnresolvedTokenLeftAsIs(t *testing.T) {
+	r := New(nil)
+
+	token := "{anything}"
+	if got := r.Replace(token); got != token {
+		t.Errorf("Replace(%q) = %q, want it left untouched", token, got)
+	}
+}
+
+func TestGeneratorProviderNow(t *testing.T) {
+	r := New(nil)
+
+	got := r.Replace("{now}")
+	if got == "{now}" || !strings.Contains(got, "T") {
+		t.Errorf(`Replace("{now}") = %q, want an RFC3339 timestamp`, got)
+	}
+}
@@ -0,0 +1,51 @@
+// Package resources walks a WADL document's nested <resource> elements
+// and concatenates each child's @path with its parent's into the full,
+// inherited path.
+package resources
+
+import (
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+)
+
+// Entry is a single <resource> element together with its fully assembled
+// path, parent segments included.
+type Entry struct {
+	Path string
+	Node *xmlquery.Node
+}
+
+// Collect walks //resources/resource recursively and returns one Entry
+// per <resource> element, in document order, with Path already
+// concatenated down from the root.
+func Collect(wadl *xmlquery.Node) []Entry {
+	var entries []Entry
+
+	var walk func(nodes []*xmlquery.Node, parentPath string)
+	walk = func(nodes []*xmlquery.Node, parentPath string) {
+		for _, node := range nodes {
+			path := Join(parentPath, node.SelectAttr("path"))
+			entries = append(entries, Entry{Path: path, Node: node})
+			walk(xmlquery.Find(node, "resource"), path)
+		}
+	}
+
+	walk(xmlquery.Find(wadl, "//resources/resource"), "")
+
+	return entries
+}
+
+// Join concatenates a parent and child WADL resource path segment,
+// collapsing the doubled slash left when one side ends, and the other
+// starts, with "/".
+func Join(parent, child string) string {
+	switch {
+	case parent == "":
+		return child
+	case child == "":
+		return parent
+	default:
+		return strings.TrimSuffix(parent, "/") + "/" + strings.TrimPrefix(child, "/")
+	}
+}
@@ -0,0 +1,55 @@
+package resources
+
+import (
+	"os"
+	"testing"
+
+	"github.com/antchfx/xmlquery"
+)
+
+func TestCollectConcatenatesNestedPaths(t *testing.T) {
+	f, err := os.Open("testdata/nested.wadl")
+	if err != nil {
+		t.Fatalf("opening fixture: %v", err)
+	}
+	defer f.Close()
+
+	wadl, err := xmlquery.Parse(f)
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+
+	entries := Collect(wadl)
+
+	want := []string{
+		"{projectId}",
+		"{projectId}/items",
+		"{projectId}/items/{id}",
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+
+	for i, entry := range entries {
+		if entry.Path != want[i] {
+			t.Errorf("entry %d: got path %q, want %q", i, entry.Path, want[i])
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	cases := []struct{ parent, child, want string }{
+		{"", "items", "items"},
+		{"items", "", "items"},
+		{"items/", "/{id}", "items/{id}"},
+		{"items", "{id}", "items/{id}"},
+		{"items/", "{id}", "items/{id}"},
+	}
+
+	for _, c := range cases {
+		if got := Join(c.parent, c.child); got != c.want {
+			t.Errorf("Join(%q, %q) = %q, want %q", c.parent, c.child, got, c.want)
+		}
+	}
+}
@@ -1,32 +1,47 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
-	"regexp"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/antchfx/xmlquery"
+
+	"github.com/0x303/wadl-dumper/internal/fetch"
+	"github.com/0x303/wadl-dumper/internal/replacer"
+	"github.com/0x303/wadl-dumper/internal/resources"
+	"github.com/0x303/wadl-dumper/wadl2openapi"
 )
 
-// Custom flag type to handle multiple -p flags
-type placeholderFlag []string
+// stringListFlag is a flag.Value that collects every occurrence of a
+// repeatable string flag, e.g. multiple -i, -p or --header.
+type stringListFlag []string
 
-func (p *placeholderFlag) String() string {
-	return fmt.Sprint(*p)
+func (s *stringListFlag) String() string {
+	return fmt.Sprint(*s)
 }
 
-func (p *placeholderFlag) Set(value string) error {
-	*p = append(*p, value)
+func (s *stringListFlag) Set(value string) error {
+	*s = append(*s, value)
 	return nil
 }
 
 type options struct {
-	baseURL, input, replace string
-	showBase                bool
-	placeholders            map[string]string
-	placeholderArgs         placeholderFlag
+	replace, format, output, inputList string
+	showBase, showMethods, curlMode    bool
+	concurrency, retries               int
+	timeout                            time.Duration
+	placeholders                       map[string]string
+	placeholderArgs                    stringListFlag
+	inputs                             stringListFlag
+	headers                            stringListFlag
 }
 
 var opt *options
@@ -36,8 +51,11 @@ func init() {
 		placeholders: make(map[string]string),
 	}
 
-	flag.StringVar(&opt.input, "i", "", "")
-	flag.StringVar(&opt.input, "input", "", "")
+	flag.Var(&opt.inputs, "i", "")
+	flag.Var(&opt.inputs, "input", "")
+
+	flag.StringVar(&opt.inputList, "I", "", "")
+	flag.StringVar(&opt.inputList, "input-list", "", "")
 
 	flag.BoolVar(&opt.showBase, "b", false, "")
 	flag.BoolVar(&opt.showBase, "show-base", false, "")
@@ -45,6 +63,18 @@ func init() {
 	flag.StringVar(&opt.replace, "r", "", "")
 	flag.StringVar(&opt.replace, "replace", "", "")
 
+	flag.BoolVar(&opt.showMethods, "methods", false, "")
+	flag.BoolVar(&opt.curlMode, "curl", false, "")
+	flag.StringVar(&opt.format, "format", "plain", "")
+
+	flag.StringVar(&opt.output, "o", "", "")
+	flag.StringVar(&opt.output, "output", "", "")
+
+	flag.IntVar(&opt.concurrency, "concurrency", runtime.NumCPU(), "")
+	flag.DurationVar(&opt.timeout, "timeout", 30*time.Second, "")
+	flag.IntVar(&opt.retries, "retries", 0, "")
+	flag.Var(&opt.headers, "header", "")
+
 	// Add custom flag for placeholders
 	flag.Var(&opt.placeholderArgs, "p", "Specify placeholder value (format: name=value)")
 	flag.Var(&opt.placeholderArgs, "placeholder", "Specify placeholder value (format: name=value)")
@@ -55,12 +85,22 @@ func init() {
 			"  wadl-dumper -i http://domain.tld/application.wadl [options...]",
 			"  wadl-dumper -i /path/to/wadl.xml --show-base -r \"-alert(1)-\"",
 			"  wadl-dumper -i /path/to/wadl.xml -p slug=myslug -p projectId=test123",
+			"  wadl-dumper -I urls.txt --concurrency 8 --header \"Authorization: Bearer tok\"",
 			"",
 			"Options:",
-			"  -i, --input <URL/FILE>         URL/path to WADL file",
+			"  -i, --input <URL/FILE>         URL/path to WADL file (repeatable)",
+			"  -I, --input-list <FILE>        File with one WADL URL/path per line",
 			"  -b, --show-base                Add base URL to paths",
 			"  -r, --replace <string>         Replace all unspecified placeholders with given value",
 			"  -p, --placeholder <name=value> Replace specific placeholder with given value (can be used multiple times)",
+			"      --methods                  Prefix each path with its HTTP method(s), e.g. \"GET /users/{id}\"",
+			"      --curl                     Emit ready-to-run curl commands instead of bare paths (implies --methods)",
+			"      --format <plain|curl|openapi>  Select the output emitter (default \"plain\")",
+			"  -o, --output <file>            Write output to file instead of stdout (openapi format, single input only)",
+			"      --concurrency <N>          Number of inputs to process in parallel (default: number of CPUs)",
+			"      --timeout <duration>       Per-request HTTP timeout, e.g. \"10s\" (default 30s)",
+			"      --retries <N>              HTTP retries with exponential backoff on network/5xx errors (default 0)",
+			"      --header <K: V>            Header to send with HTTP requests (can be used multiple times)",
 			"  -h, --help                     Show its help text",
 			"",
 		}
@@ -79,106 +119,296 @@ func parsePlaceholders() {
 	}
 }
 
+// parseHeaders turns "K: V" flag values into a header name/value map.
+func parseHeaders(raw []string) (map[string]string, error) {
+	headers := make(map[string]string, len(raw))
+
+	for _, h := range raw {
+		parts := strings.SplitN(h, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid --header %q, expected \"K: V\"", h)
+		}
+
+		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return headers, nil
+}
+
+// resolveSources gathers every -i/--input value plus, if -I/--input-list
+// is set, one source per non-blank, non-comment line of that file.
+func resolveSources() ([]string, error) {
+	sources := append([]string{}, opt.inputs...)
+
+	if opt.inputList == "" {
+		return sources, nil
+	}
+
+	f, err := os.Open(opt.inputList)
+	if err != nil {
+		return nil, fmt.Errorf("can't open '%s' file", opt.inputList)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sources = append(sources, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading '%s' file: %w", opt.inputList, err)
+	}
+
+	return sources, nil
+}
+
 func errorExit(message string) {
 	err := fmt.Sprintf("Error! %s\n", message)
 	fmt.Fprint(os.Stderr, err)
 	os.Exit(1)
 }
 
-func replaceNth(s, old string, new string, n int) string {
-	i := 0
+// paramSuffix builds a ";a={a}" matrix prefix and/or a "?b={b}&c={c}"
+// query suffix from a method's request params, ready to be run through a
+// replacer.Replacer.
+func paramSuffix(method *xmlquery.Node) string {
+	if method == nil {
+		return ""
+	}
+
+	var suffix strings.Builder
 
-	for m := 1; m <= n; m++ {
-		x := strings.Index(s[i:], old)
-		if x < 0 {
-			break
-		}
-		i += x
-		if m == n {
-			return s[:i] + new + s[i+len(old):]
+	for _, name := range paramNames(method, "matrix") {
+		fmt.Fprintf(&suffix, ";%s={%s}", name, name)
+	}
+
+	if query := paramNames(method, "query"); len(query) > 0 {
+		parts := make([]string, len(query))
+		for i, name := range query {
+			parts[i] = fmt.Sprintf("%s={%s}", name, name)
 		}
-		i += len(old)
+		suffix.WriteString("?" + strings.Join(parts, "&"))
+	}
+
+	return suffix.String()
+}
+
+// paramNames returns the names of a method's request params of the given
+// style ("query", "matrix", ...), in document order.
+func paramNames(method *xmlquery.Node, style string) []string {
+	nodes := xmlquery.Find(method, fmt.Sprintf("request/param[@style='%s']/@name", style))
+
+	names := make([]string, len(nodes))
+	for i, n := range nodes {
+		names[i] = n.InnerText()
 	}
 
-	return s
+	return names
+}
+
+// stdoutMu serializes writes to stdout across the worker pool so lines
+// from different sources are never interleaved mid-line.
+var stdoutMu sync.Mutex
+
+func printBlock(s string) {
+	stdoutMu.Lock()
+	defer stdoutMu.Unlock()
+	fmt.Print(s)
 }
 
-// replacePlaceholders replaces placeholders in the path with values from the placeholders map
-// or with the default replace value if specified
-func replacePlaceholders(path string) string {
-	// Regex to find placeholders in the format {name}
-	re := regexp.MustCompile(`\{([^{}]+)\}`)
+// prefixFor tags each output line with its source when more than one
+// input is being processed, so concurrent results stay attributable.
+func prefixFor(source string, multi bool) string {
+	if !multi {
+		return ""
+	}
+	return fmt.Sprintf("[%s] ", source)
+}
+
+// processSource loads and dumps a single WADL source, writing its output
+// (or, in openapi format, its document) out as one block.
+func processSource(source string, client *fetch.Client, multi bool) error {
+	wadl, err := client.Load(source)
+	if err != nil {
+		return err
+	}
+
+	if wadl == nil {
+		return fmt.Errorf("can't parse WADL file")
+	}
+
+	xmlns := xmlquery.FindOne(wadl, "//application/@xmlns")
+	if xmlns == nil || !strings.Contains(xmlns.InnerText(), "wadl.dev.java.net") {
+		return fmt.Errorf("not a WADL file")
+	}
+
+	if opt.format == "openapi" {
+		return emitOpenAPI(wadl, source)
+	}
+
+	replacerOpts := []replacer.Option{replacer.WithDefault(opt.replace)}
+	if strings.HasPrefix(source, "http") {
+		// The WADL itself is untrusted input here: its own @path/@name
+		// strings are what gets resolved, so don't let a crafted one read
+		// local files, leak env vars, or hit an attacker-controlled URL.
+		replacerOpts = append(replacerOpts, replacer.WithUntrustedSource())
+	}
+	rep := replacer.New(opt.placeholders, replacerOpts...)
+
+	baseURL := ""
+	if base := xmlquery.FindOne(wadl, "//resources/@base"); base != nil && opt.showBase {
+		baseURL = base.InnerText()
+	}
+
+	prefix := prefixFor(source, multi)
 
-	// Use a replacement function to handle each match
-	result := re.ReplaceAllStringFunc(path, func(match string) string {
-		// Extract the placeholder name without braces
-		name := match[1 : len(match)-1]
+	var out strings.Builder
+	for _, entry := range resources.Collect(wadl) {
+		methods := xmlquery.Find(entry.Node, "method")
 
-		// Check if we have a specific value for this placeholder
-		if value, exists := opt.placeholders[name]; exists {
-			return value
+		if !opt.showMethods && opt.format == "plain" {
+			fmt.Fprintf(&out, "%s%s\n", prefix, rep.Replace(resources.Join(baseURL, entry.Path)))
+			continue
 		}
 
-		// If no specific value but we have a default replace value, use that
-		if opt.replace != "" {
-			return opt.replace
+		if len(methods) == 0 {
+			methods = []*xmlquery.Node{nil}
 		}
 
-		// Otherwise, leave the placeholder as is
-		return match
-	})
+		for _, method := range methods {
+			name := "GET"
+			if method != nil {
+				name = method.SelectAttr("name")
+			}
+
+			path := rep.Replace(resources.Join(baseURL, entry.Path) + paramSuffix(method))
+
+			if opt.format == "curl" {
+				fmt.Fprintf(&out, "%scurl -X %s \"%s\"\n", prefix, name, path)
+			} else {
+				fmt.Fprintf(&out, "%s%s %s\n", prefix, name, path)
+			}
+		}
+	}
+
+	printBlock(out.String())
+	return nil
+}
+
+func emitOpenAPI(wadl *xmlquery.Node, source string) error {
+	title := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+
+	doc, err := wadl2openapi.Convert(wadl, title, opt.placeholders)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if opt.output == "" {
+		printBlock(string(out) + "\n")
+		return nil
+	}
 
-	return result
+	if err := os.WriteFile(opt.output, append(out, '\n'), 0644); err != nil {
+		return fmt.Errorf("can't write to '%s' file: %w", opt.output, err)
+	}
+
+	return nil
 }
 
 func main() {
 	flag.Parse()
 	parsePlaceholders()
 
-	var path string
-	var wadl *xmlquery.Node
-
-	if opt.input == "" {
-		errorExit("Flag -i is required, use -h flag for help.")
+	if opt.curlMode {
+		opt.format = "curl"
 	}
 
-	if strings.HasPrefix(opt.input, "http") {
-		wadl, _ = xmlquery.LoadURL(opt.input)
-	} else {
-		f, err := os.Open(opt.input)
-		if err != nil {
-			errorExit(fmt.Sprintf("Can't open '%s' file.", opt.input))
-		}
+	switch opt.format {
+	case "plain", "curl", "openapi":
+	default:
+		errorExit(fmt.Sprintf("Unknown --format %q, expected plain, curl or openapi.", opt.format))
+	}
 
-		wadl, _ = xmlquery.Parse(f)
+	sources, err := resolveSources()
+	if err != nil {
+		errorExit(err.Error())
 	}
 
-	if wadl == nil {
-		errorExit("Can't parse WADL file.")
+	if len(sources) == 0 {
+		errorExit("Flag -i (or -I) is required, use -h flag for help.")
 	}
 
-	xmlns := xmlquery.FindOne(wadl, "//application/@xmlns")
-	if xmlns == nil || !strings.Contains(xmlns.InnerText(), "wadl.dev.java.net") {
-		errorExit("Not a WADL file.")
+	if opt.format == "openapi" && opt.output != "" && len(sources) > 1 {
+		errorExit("-o/--output can only be used with a single input.")
 	}
 
-	base := xmlquery.FindOne(wadl, "//resources/@base")
-	if base != nil && opt.showBase {
-		opt.baseURL = base.InnerText()
-	} else {
-		opt.baseURL = ""
+	headers, err := parseHeaders(opt.headers)
+	if err != nil {
+		errorExit(err.Error())
 	}
 
-	for _, paths := range xmlquery.Find(wadl, "//resource/@path") {
-		path = opt.baseURL + paths.InnerText()
+	clientOpts := []fetch.Option{fetch.WithRetries(opt.retries)}
+	for key, value := range headers {
+		clientOpts = append(clientOpts, fetch.WithHeader(key, value))
+	}
+	client := fetch.New(opt.timeout, clientOpts...)
 
-		// Apply placeholder replacements
-		path = replacePlaceholders(path)
+	failures := processAll(sources, client, opt.concurrency)
 
-		if opt.baseURL != "" {
-			path = replaceNth(path, "//", "/", 2)
+	if len(failures) > 0 {
+		for _, f := range failures {
+			fmt.Fprintf(os.Stderr, "Error! %s\n", f)
 		}
+		os.Exit(1)
+	}
+}
 
-		fmt.Printf("%s\n", path)
+// processAll runs processSource for every source across a worker pool of
+// the given size (clamped to [1, len(sources)]), returning one formatted
+// "source: error" message per failure instead of bailing out on the
+// first one.
+func processAll(sources []string, client *fetch.Client, concurrency int) []string {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(sources) {
+		concurrency = len(sources)
 	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failures []string
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for source := range jobs {
+				if err := processSource(source, client, len(sources) > 1); err != nil {
+					mu.Lock()
+					failures = append(failures, fmt.Sprintf("%s: %v", source, err))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, source := range sources {
+		jobs <- source
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return failures
 }
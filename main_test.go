@@ -0,0 +1,202 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/0x303/wadl-dumper/internal/fetch"
+	"github.com/0x303/wadl-dumper/internal/resources"
+)
+
+// withOpt runs fn with a scratch copy of opt installed, restoring the
+// original afterwards so tests can't leak flag state into each other.
+func withOpt(t *testing.T, fn func()) {
+	t.Helper()
+
+	saved := *opt
+	t.Cleanup(func() { *opt = saved })
+
+	fn()
+}
+
+func loadFixture(t *testing.T, path string) *xmlquery.Node {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("opening fixture: %v", err)
+	}
+	defer f.Close()
+
+	wadl, err := xmlquery.Parse(f)
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+
+	return wadl
+}
+
+func TestParamSuffix(t *testing.T) {
+	wadl := loadFixture(t, "testdata/nested.wadl")
+	entries := resources.Collect(wadl)
+
+	cases := map[string]string{
+		"{projectId}/items":      "?a={a}&b={b}",
+		"{projectId}/items/{id}": ";rev={rev}",
+	}
+
+	for _, entry := range entries {
+		want, ok := cases[entry.Path]
+		if !ok {
+			continue
+		}
+
+		for _, method := range xmlquery.Find(entry.Node, "method") {
+			if method.SelectAttr("name") != "GET" {
+				continue
+			}
+
+			if got := paramSuffix(method); got != want {
+				t.Errorf("paramSuffix(%s GET) = %q, want %q", entry.Path, got, want)
+			}
+		}
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	got, err := parseHeaders([]string{"Authorization: Bearer tok", "X-Trace-Id:abc"})
+	if err != nil {
+		t.Fatalf("parseHeaders: %v", err)
+	}
+
+	want := map[string]string{
+		"Authorization": "Bearer tok",
+		"X-Trace-Id":    "abc",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d headers, want %d: %+v", len(got), len(want), got)
+	}
+
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("header %q = %q, want %q", name, got[name], value)
+		}
+	}
+
+	if _, err := parseHeaders([]string{"not-a-header"}); err == nil {
+		t.Error("parseHeaders(malformed) = nil error, want one")
+	}
+}
+
+func TestPrefixFor(t *testing.T) {
+	if got := prefixFor("a.wadl", false); got != "" {
+		t.Errorf("prefixFor(single) = %q, want empty", got)
+	}
+
+	if got := prefixFor("a.wadl", true); got != "[a.wadl] " {
+		t.Errorf("prefixFor(multi) = %q, want %q", got, "[a.wadl] ")
+	}
+}
+
+func TestResolveSourcesCombinesInputsAndList(t *testing.T) {
+	withOpt(t, func() {
+		listPath := filepath.Join(t.TempDir(), "list.txt")
+		content := "# a comment\n\ntestdata/nested.wadl\n   \nhttp://example.com/app.wadl\n"
+		if err := os.WriteFile(listPath, []byte(content), 0o644); err != nil {
+			t.Fatalf("writing fixture: %v", err)
+		}
+
+		opt.inputs = stringListFlag{"a.wadl"}
+		opt.inputList = listPath
+
+		got, err := resolveSources()
+		if err != nil {
+			t.Fatalf("resolveSources: %v", err)
+		}
+
+		want := []string{"a.wadl", "testdata/nested.wadl", "http://example.com/app.wadl"}
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("source %d = %q, want %q", i, got[i], want[i])
+			}
+		}
+	})
+}
+
+func TestResolveSourcesMissingListFile(t *testing.T) {
+	withOpt(t, func() {
+		opt.inputs = nil
+		opt.inputList = filepath.Join(t.TempDir(), "missing.txt")
+
+		if _, err := resolveSources(); err == nil {
+			t.Error("resolveSources() = nil error, want one for a missing -I file")
+		}
+	})
+}
+
+func TestProcessAllAggregatesFailuresAcrossTheWorkerPool(t *testing.T) {
+	withOpt(t, func() {
+		opt.format = "plain"
+		opt.placeholders = map[string]string{}
+
+		sources := []string{
+			"testdata/nested.wadl",
+			filepath.Join(t.TempDir(), "missing.wadl"),
+			"testdata/nested.wadl",
+		}
+
+		failures := processAll(sources, fetch.New(time.Second), 2)
+
+		if len(failures) != 1 {
+			t.Fatalf("got %d failures, want 1: %v", len(failures), failures)
+		}
+
+		if !strings.Contains(failures[0], "missing.wadl") {
+			t.Errorf("failure %q doesn't reference the missing source", failures[0])
+		}
+	})
+}
+
+func TestProcessAllSucceedsWithoutFailures(t *testing.T) {
+	withOpt(t, func() {
+		opt.format = "plain"
+		opt.placeholders = map[string]string{}
+
+		sources := []string{"testdata/nested.wadl", "testdata/nested.wadl"}
+
+		if failures := processAll(sources, fetch.New(time.Second), 2); len(failures) != 0 {
+			t.Errorf("got failures %v, want none", failures)
+		}
+	})
+}
+
+func TestNestedResourcesJoinBaseURL(t *testing.T) {
+	wadl := loadFixture(t, "testdata/nested.wadl")
+	entries := resources.Collect(wadl)
+
+	want := []string{
+		"https://api.example.com/{projectId}",
+		"https://api.example.com/{projectId}/items",
+		"https://api.example.com/{projectId}/items/{id}",
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+
+	for i, entry := range entries {
+		if got := resources.Join("https://api.example.com/", entry.Path); got != want[i] {
+			t.Errorf("entry %d: got %q, want %q", i, got, want[i])
+		}
+	}
+}
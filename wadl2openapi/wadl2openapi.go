@@ -0,0 +1,217 @@
+// Package wadl2openapi translates a parsed WADL document into an OpenAPI
+// 3.0 document.
+package wadl2openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/0x303/wadl-dumper/internal/resources"
+)
+
+// Document is the root of an OpenAPI 3.0 document.
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Servers []Server            `json:"servers,omitempty"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+// Info describes the generated API, per the OpenAPI spec.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Server is a single entry of the top-level `servers` array.
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem maps lower-cased HTTP methods (get, post, ...) to their Operation.
+type PathItem map[string]Operation
+
+// Operation is a single method on a path.
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a path/query/header parameter, per WADL param@style.
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required,omitempty"`
+	Schema   Schema `json:"schema"`
+	Example  string `json:"example,omitempty"`
+}
+
+// Schema is deliberately minimal: WADL doesn't carry enough type
+// information to infer anything beyond "string".
+type Schema struct {
+	Type string `json:"type"`
+}
+
+// RequestBody wraps the media types accepted by an operation.
+type RequestBody struct {
+	Content map[string]MediaType `json:"content"`
+}
+
+// Response is a single entry of an operation's `responses` map.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is a single entry of a `content` map, keyed by media type.
+type MediaType struct {
+	Schema Schema `json:"schema,omitempty"`
+}
+
+// Convert walks the resources of a parsed WADL document and builds the
+// equivalent OpenAPI 3.0 Document. placeholders maps template/query param
+// names to the example values resolved from `-p` flags, so callers can see
+// what a call to the generated paths would look like.
+func Convert(wadl *xmlquery.Node, title string, placeholders map[string]string) (*Document, error) {
+	if wadl == nil {
+		return nil, fmt.Errorf("wadl2openapi: nil document")
+	}
+
+	doc := &Document{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: title, Version: "1.0.0"},
+		Paths:   make(map[string]PathItem),
+	}
+
+	if base := xmlquery.FindOne(wadl, "//resources/@base"); base != nil {
+		doc.Servers = append(doc.Servers, Server{URL: base.InnerText()})
+	}
+
+	for _, entry := range resources.Collect(wadl) {
+		resourcePath := entry.Path
+		item := PathItem{}
+
+		for _, method := range xmlquery.Find(entry.Node, "method") {
+			name := method.SelectAttr("name")
+			op := Operation{
+				OperationID: strings.ToLower(name) + strings.ReplaceAll(resourcePath, "/", "_"),
+				Responses:   map[string]Response{"200": {Description: "OK"}},
+			}
+
+			op.Parameters = append(op.Parameters, templateParameters(resourcePath, placeholders)...)
+			op.Parameters = append(op.Parameters, queryAndHeaderParameters(method, placeholders)...)
+
+			if body := requestBody(method); body != nil {
+				op.RequestBody = body
+			}
+
+			if responses := representationResponses(method); len(responses) > 0 {
+				op.Responses = responses
+			}
+
+			item[strings.ToLower(name)] = op
+		}
+
+		if len(item) > 0 {
+			doc.Paths["/"+strings.TrimPrefix(resourcePath, "/")] = item
+		}
+	}
+
+	return doc, nil
+}
+
+// templateParameters extracts `{name}` placeholders from a resource path and
+// turns them into required "path" parameters.
+func templateParameters(path string, placeholders map[string]string) []Parameter {
+	var params []Parameter
+
+	for _, segment := range strings.Split(path, "/") {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name := segment[1 : len(segment)-1]
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   Schema{Type: "string"},
+			Example:  placeholders[name],
+		})
+	}
+
+	return params
+}
+
+// queryAndHeaderParameters maps WADL `param[@style=query|header]` elements
+// of a method's request to OpenAPI parameters.
+func queryAndHeaderParameters(method *xmlquery.Node, placeholders map[string]string) []Parameter {
+	var params []Parameter
+
+	for _, style := range []string{"query", "header"} {
+		for _, p := range xmlquery.Find(method, fmt.Sprintf("request/param[@style='%s']", style)) {
+			name := p.SelectAttr("name")
+			params = append(params, Parameter{
+				Name:     name,
+				In:       style,
+				Required: p.SelectAttr("required") == "true",
+				Schema:   Schema{Type: "string"},
+				Example:  placeholders[name],
+			})
+		}
+	}
+
+	return params
+}
+
+// requestBody maps a method's `request/representation/@mediaType` entries to
+// an OpenAPI requestBody, or nil if the method takes no body.
+func requestBody(method *xmlquery.Node) *RequestBody {
+	reps := xmlquery.Find(method, "request/representation")
+	if len(reps) == 0 {
+		return nil
+	}
+
+	content := make(map[string]MediaType)
+	for _, r := range reps {
+		mediaType := r.SelectAttr("mediaType")
+		if mediaType == "" {
+			continue
+		}
+		content[mediaType] = MediaType{}
+	}
+
+	if len(content) == 0 {
+		return nil
+	}
+
+	return &RequestBody{Content: content}
+}
+
+// representationResponses maps a method's `response/representation` entries
+// to OpenAPI responses, keyed by the response's `status` attribute.
+func representationResponses(method *xmlquery.Node) map[string]Response {
+	responses := make(map[string]Response)
+
+	for _, resp := range xmlquery.Find(method, "response") {
+		status := resp.SelectAttr("status")
+		if status == "" {
+			status = "200"
+		}
+
+		content := make(map[string]MediaType)
+		for _, r := range xmlquery.Find(resp, "representation") {
+			if mediaType := r.SelectAttr("mediaType"); mediaType != "" {
+				content[mediaType] = MediaType{}
+			}
+		}
+
+		responses[status] = Response{Description: "OK", Content: content}
+	}
+
+	return responses
+}
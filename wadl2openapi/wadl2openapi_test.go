@@ -0,0 +1,121 @@
+package wadl2openapi
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/antchfx/xmlquery"
+)
+
+func loadFixture(t *testing.T, path string) *xmlquery.Node {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("opening fixture: %v", err)
+	}
+	defer f.Close()
+
+	wadl, err := xmlquery.Parse(f)
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+
+	return wadl
+}
+
+func TestConvert(t *testing.T) {
+	wadl := loadFixture(t, "testdata/convert.wadl")
+
+	doc, err := Convert(wadl, "convert", map[string]string{"projectId": "proj-1"})
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+
+	if doc.OpenAPI != "3.0.0" {
+		t.Errorf("OpenAPI = %q, want %q", doc.OpenAPI, "3.0.0")
+	}
+
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://api.example.com/" {
+		t.Errorf("Servers = %+v, want base URL server", doc.Servers)
+	}
+
+	item, ok := doc.Paths["/{projectId}/items"]
+	if !ok {
+		t.Fatalf("Paths = %+v, missing %q", doc.Paths, "/{projectId}/items")
+	}
+
+	get, ok := item["get"]
+	if !ok {
+		t.Fatalf("item = %+v, missing GET operation", item)
+	}
+
+	wantParams := map[string]Parameter{
+		"projectId":  {Name: "projectId", In: "path", Required: true, Schema: Schema{Type: "string"}, Example: "proj-1"},
+		"limit":      {Name: "limit", In: "query", Schema: Schema{Type: "string"}},
+		"X-Trace-Id": {Name: "X-Trace-Id", In: "header", Required: true, Schema: Schema{Type: "string"}},
+	}
+
+	if len(get.Parameters) != len(wantParams) {
+		t.Fatalf("got %d parameters, want %d: %+v", len(get.Parameters), len(wantParams), get.Parameters)
+	}
+
+	for _, p := range get.Parameters {
+		want, ok := wantParams[p.Name]
+		if !ok {
+			t.Errorf("unexpected parameter %+v", p)
+			continue
+		}
+		if p != want {
+			t.Errorf("parameter %q = %+v, want %+v", p.Name, p, want)
+		}
+	}
+
+	if get.RequestBody != nil {
+		t.Errorf("GET RequestBody = %+v, want nil", get.RequestBody)
+	}
+
+	wantResp := Response{Description: "OK", Content: map[string]MediaType{"application/json": {}}}
+	if resp, ok := get.Responses["200"]; !ok || resp.Description != wantResp.Description || len(resp.Content) != 1 {
+		t.Errorf("GET Responses[200] = %+v, want %+v", get.Responses["200"], wantResp)
+	}
+
+	post, ok := item["post"]
+	if !ok {
+		t.Fatalf("item = %+v, missing POST operation", item)
+	}
+
+	if post.RequestBody == nil || len(post.RequestBody.Content) != 1 {
+		t.Errorf("POST RequestBody = %+v, want one application/json entry", post.RequestBody)
+	}
+
+	if _, ok := post.Responses["201"]; !ok {
+		t.Errorf("POST Responses = %+v, missing 201", post.Responses)
+	}
+
+	if post.OperationID != "post{projectId}_items" {
+		t.Errorf("POST OperationID = %q, want %q", post.OperationID, "post{projectId}_items")
+	}
+}
+
+func TestConvertPathKeysHaveLeadingSlash(t *testing.T) {
+	wadl := loadFixture(t, "testdata/convert.wadl")
+
+	doc, err := Convert(wadl, "convert", nil)
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+
+	for path := range doc.Paths {
+		if !strings.HasPrefix(path, "/") {
+			t.Errorf("path key %q doesn't start with %q, required by the OpenAPI Path Item Object", path, "/")
+		}
+	}
+}
+
+func TestConvertNilDocument(t *testing.T) {
+	if _, err := Convert(nil, "title", nil); err == nil {
+		t.Error("Convert(nil) = nil error, want one")
+	}
+}